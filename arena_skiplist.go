@@ -0,0 +1,351 @@
+// Copyright (c) 2021, Rod Dong <rod.dong@me.com> All rights reserved.
+//
+// Use of this source code is governed by The MIT License.
+
+package skiplist
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"unsafe"
+)
+
+// concurrentMaxHeight bounds the tower height of a ConcurrentSkiplist node.
+const concurrentMaxHeight = 20
+
+// ErrArenaFull is returned by Add when the arena has no room left for the
+// new node, key and value. Callers should treat it as a signal to flush and
+// build a fresh ConcurrentSkiplist, the same way a memtable is rotated once
+// it is full.
+var ErrArenaFull = errors.New("skiplist: arena is full")
+
+// ErrDuplicateKey is returned by Add when the key already exists.
+var ErrDuplicateKey = errors.New("skiplist: duplicate key")
+
+// arena is a preallocated byte slice bump allocator. Nodes are carved out of
+// it as fixed layouts addressed by uint32 offset instead of Go pointers, so
+// the structure holds no per-node GC references, following the Badger/Pebble
+// inline skiplist design. Offset 0 is reserved and always means "no node".
+type arena struct {
+	n   uint32
+	buf []byte
+}
+
+func newArena(size int64) *arena {
+	return &arena{
+		buf: make([]byte, size),
+		n:   4, // skip the first 4 bytes so offset 0 can mean "nil"
+	}
+}
+
+// allocate reserves size bytes (rounded up to a multiple of 4, so returned
+// offsets stay aligned for atomic access) and returns their offset.
+func (a *arena) allocate(size uint32) (uint32, error) {
+	padded := (size + 3) &^ 3
+	offset := atomic.AddUint32(&a.n, padded) - padded
+	if int64(offset)+int64(padded) > int64(len(a.buf)) {
+		return 0, ErrArenaFull
+	}
+	return offset, nil
+}
+
+func (a *arena) putBytes(b []byte) (uint32, error) {
+	if len(b) == 0 {
+		return 0, nil
+	}
+	offset, err := a.allocate(uint32(len(b)))
+	if err != nil {
+		return 0, err
+	}
+	copy(a.buf[offset:offset+uint32(len(b))], b)
+	return offset, nil
+}
+
+func (a *arena) getBytes(offset, size uint32) []byte {
+	if size == 0 {
+		return nil
+	}
+	return a.buf[offset : offset+size]
+}
+
+// concurrentNode layout, starting at a node's offset:
+//
+//	keyOffset   uint32
+//	keySize     uint32
+//	valueOffset uint32
+//	valueSize   uint32
+//	height      uint32
+//	tower       [height]uint32 (next-node offsets, one per level)
+const concurrentNodeHeaderSize = 20
+
+func (a *arena) putNode(height int, keyOffset, keySize, valueOffset, valueSize uint32) (uint32, error) {
+	offset, err := a.allocate(uint32(concurrentNodeHeaderSize + height*4))
+	if err != nil {
+		return 0, err
+	}
+	binaryPutUint32(a.buf, offset+0, keyOffset)
+	binaryPutUint32(a.buf, offset+4, keySize)
+	binaryPutUint32(a.buf, offset+8, valueOffset)
+	binaryPutUint32(a.buf, offset+12, valueSize)
+	binaryPutUint32(a.buf, offset+16, uint32(height))
+	return offset, nil
+}
+
+func (a *arena) nodeKey(nodeOffset uint32) []byte {
+	keyOffset := binaryUint32(a.buf, nodeOffset+0)
+	keySize := binaryUint32(a.buf, nodeOffset+4)
+	return a.getBytes(keyOffset, keySize)
+}
+
+func (a *arena) nodeValue(nodeOffset uint32) []byte {
+	valueOffset := binaryUint32(a.buf, nodeOffset+8)
+	valueSize := binaryUint32(a.buf, nodeOffset+12)
+	return a.getBytes(valueOffset, valueSize)
+}
+
+func (a *arena) towerSlot(nodeOffset uint32, level int) *uint32 {
+	off := nodeOffset + concurrentNodeHeaderSize + uint32(level)*4
+	return (*uint32)(unsafe.Pointer(&a.buf[off]))
+}
+
+func (a *arena) getNextOffset(nodeOffset uint32, level int) uint32 {
+	if nodeOffset == 0 {
+		return 0
+	}
+	return atomic.LoadUint32(a.towerSlot(nodeOffset, level))
+}
+
+func (a *arena) setNextOffset(nodeOffset uint32, level int, next uint32) {
+	atomic.StoreUint32(a.towerSlot(nodeOffset, level), next)
+}
+
+func (a *arena) casNextOffset(nodeOffset uint32, level int, old, new uint32) bool {
+	return atomic.CompareAndSwapUint32(a.towerSlot(nodeOffset, level), old, new)
+}
+
+func binaryPutUint32(buf []byte, offset, v uint32) {
+	buf[offset] = byte(v)
+	buf[offset+1] = byte(v >> 8)
+	buf[offset+2] = byte(v >> 16)
+	buf[offset+3] = byte(v >> 24)
+}
+
+func binaryUint32(buf []byte, offset uint32) uint32 {
+	return uint32(buf[offset]) | uint32(buf[offset+1])<<8 | uint32(buf[offset+2])<<16 | uint32(buf[offset+3])<<24
+}
+
+// ConcurrentSkiplist is a lock-free skiplist variant intended for use as a
+// memtable: nodes are carved out of a preallocated arena instead of being
+// `make`-allocated one at a time, insertion is done with a CAS retry loop
+// instead of locking, and lookups are wait-free. It is built for
+// high-throughput single-writer/multi-reader workloads, the same niche the
+// Badger/Pebble inline skiplist fills. The ordinary Skiplist[K,V] is
+// unaffected and remains the general-purpose, GC-friendly implementation.
+type ConcurrentSkiplist struct {
+	height     int32 // atomic, current max tower height in use
+	headOffset uint32
+	arena      *arena
+}
+
+// NewConcurrentSkiplist returns an empty ConcurrentSkiplist backed by an
+// arena of arenaSize bytes.
+func NewConcurrentSkiplist(arenaSize int64) *ConcurrentSkiplist {
+	a := newArena(arenaSize)
+	headOffset, err := a.putNode(concurrentMaxHeight, 0, 0, 0, 0)
+	if err != nil {
+		// arenaSize too small even for the head node; this is a construction
+		// error, not a runtime one, so panic rather than return it.
+		panic(err)
+	}
+	return &ConcurrentSkiplist{
+		height:     1,
+		headOffset: headOffset,
+		arena:      a,
+	}
+}
+
+// MemSize returns the number of arena bytes consumed so far.
+func (s *ConcurrentSkiplist) MemSize() int64 {
+	return int64(atomic.LoadUint32(&s.arena.n))
+}
+
+func (s *ConcurrentSkiplist) getHeight() int32 {
+	return atomic.LoadInt32(&s.height)
+}
+
+// randomHeight draws a tower height using the package's geometric
+// distribution (growth probability P), capped at concurrentMaxHeight rather
+// than MaxLevel.
+func randomHeight() int {
+	height := 1
+	for height < concurrentMaxHeight {
+		v := rand.Uint32()
+		if float32(v&0xFFFF) > float32(P*0xFFFF) {
+			break
+		}
+		height++
+	}
+	return height
+}
+
+// findSpliceForLevel walks forward from the node at "start" at the given
+// level, returning the last node before key and the first node with a key
+// >= key. found reports an exact key match.
+func (s *ConcurrentSkiplist) findSpliceForLevel(key []byte, level int, start uint32) (prev, next uint32, found bool) {
+	prev = start
+	for {
+		next = s.arena.getNextOffset(prev, level)
+		if next == 0 {
+			return prev, next, false
+		}
+		switch bytes.Compare(key, s.arena.nodeKey(next)) {
+		case 0:
+			return prev, next, true
+		case -1:
+			return prev, next, false
+		}
+		prev = next
+	}
+}
+
+// Add inserts key/value. It returns ErrDuplicateKey if key is already
+// present, or ErrArenaFull if the arena has no room left.
+func (s *ConcurrentSkiplist) Add(key, value []byte) error {
+	listHeight := s.getHeight()
+	var prev, next [concurrentMaxHeight + 1]uint32
+	prev[listHeight] = s.headOffset
+
+	for i := int(listHeight) - 1; i >= 0; i-- {
+		p, n, found := s.findSpliceForLevel(key, i, prev[i+1])
+		if found {
+			return ErrDuplicateKey
+		}
+		prev[i], next[i] = p, n
+	}
+
+	height := randomHeight()
+	splicedHeight := listHeight
+	for height > int(listHeight) {
+		if atomic.CompareAndSwapInt32(&s.height, listHeight, int32(height)) {
+			break
+		}
+		listHeight = s.getHeight()
+	}
+
+	// Levels from splicedHeight up to the (possibly grown) height were never
+	// visited by the descent loop above, so prev/next are still zero-valued
+	// there. Offset 0 means "no node", and using it as a prev node would make
+	// the CAS below write into the head node's own fields, so those levels
+	// must be seeded explicitly: a taller tower starts with nothing after it,
+	// directly off the header.
+	for i := int(splicedHeight); i < height; i++ {
+		prev[i] = s.headOffset
+		next[i] = 0
+	}
+
+	keyOffset, err := s.arena.putBytes(key)
+	if err != nil {
+		return err
+	}
+	valueOffset, err := s.arena.putBytes(value)
+	if err != nil {
+		return err
+	}
+	nodeOffset, err := s.arena.putNode(height, keyOffset, uint32(len(key)), valueOffset, uint32(len(value)))
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < height; i++ {
+		for {
+			s.arena.setNextOffset(nodeOffset, i, next[i])
+			if s.arena.casNextOffset(prev[i], i, next[i], nodeOffset) {
+				break
+			}
+			// Someone else spliced in at this level; re-run the walk and
+			// re-check for a duplicate before retrying the CAS.
+			p, n, found := s.findSpliceForLevel(key, i, prev[i])
+			if found {
+				return ErrDuplicateKey
+			}
+			prev[i], next[i] = p, n
+		}
+	}
+	return nil
+}
+
+// Get returns the value stored for key. If not found, error(Not Found).
+func (s *ConcurrentSkiplist) Get(key []byte) ([]byte, error) {
+	node := s.headOffset
+	for i := int(s.getHeight()) - 1; i >= 0; i-- {
+		for {
+			next := s.arena.getNextOffset(node, i)
+			if next == 0 {
+				break
+			}
+			cmp := bytes.Compare(key, s.arena.nodeKey(next))
+			if cmp == 0 {
+				return s.arena.nodeValue(next), nil
+			}
+			if cmp < 0 {
+				break
+			}
+			node = next
+		}
+	}
+	return nil, errors.New("Not Found")
+}
+
+// Contains reports whether key is present.
+func (s *ConcurrentSkiplist) Contains(key []byte) bool {
+	_, err := s.Get(key)
+	return err == nil
+}
+
+// ConcurrentIterator walks a ConcurrentSkiplist forward over arena offsets.
+// It is snapshot-consistent: once positioned, concurrent Adds elsewhere in
+// the arena do not change what it sees at or before its current node.
+type ConcurrentIterator struct {
+	s      *ConcurrentSkiplist
+	offset uint32
+}
+
+// NewIterator returns a forward ConcurrentIterator positioned before the
+// first entry; call SeekToFirst or Next to advance onto it. Offset 0 is
+// reserved for "no node" (see arena), so it also marks "not yet positioned".
+func (s *ConcurrentSkiplist) NewIterator() *ConcurrentIterator {
+	return &ConcurrentIterator{s: s, offset: 0}
+}
+
+// Valid reports whether the iterator is positioned at an entry.
+func (it *ConcurrentIterator) Valid() bool {
+	return it.offset != 0
+}
+
+// Key returns the key at the current position. Valid() must be true.
+func (it *ConcurrentIterator) Key() []byte {
+	return it.s.arena.nodeKey(it.offset)
+}
+
+// Value returns the value at the current position. Valid() must be true.
+func (it *ConcurrentIterator) Value() []byte {
+	return it.s.arena.nodeValue(it.offset)
+}
+
+// Next advances the iterator to the next key in ascending order. Calling it
+// before the iterator has ever been positioned moves it onto the first key,
+// the same as SeekToFirst.
+func (it *ConcurrentIterator) Next() {
+	if it.offset == 0 {
+		it.offset = it.s.arena.getNextOffset(it.s.headOffset, 0)
+		return
+	}
+	it.offset = it.s.arena.getNextOffset(it.offset, 0)
+}
+
+// SeekToFirst positions the iterator at the first (smallest) key.
+func (it *ConcurrentIterator) SeekToFirst() {
+	it.offset = it.s.arena.getNextOffset(it.s.headOffset, 0)
+}
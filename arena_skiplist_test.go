@@ -0,0 +1,134 @@
+package skiplist
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func buildConcurrentSkiplist() *ConcurrentSkiplist {
+	s := NewConcurrentSkiplist(1 << 16)
+
+	s.Add([]byte("000"), []byte("000"))
+	s.Add([]byte("001"), []byte("001"))
+	s.Add([]byte("008"), []byte("008"))
+	s.Add([]byte("003"), []byte("003"))
+	s.Add([]byte("005"), []byte("005"))
+
+	return s
+}
+
+func TestConcurrentSkiplistAddAndGet(t *testing.T) {
+	s := buildConcurrentSkiplist()
+
+	v, err := s.Get([]byte("003"))
+	if err != nil || string(v) != "003" {
+		t.Errorf("Get(003) = %s, %v; want 003, nil", v, err)
+	}
+
+	if _, err := s.Get([]byte("009")); err == nil {
+		t.Errorf("Get(009) should return error(Not Found)")
+	}
+
+	if !s.Contains([]byte("005")) {
+		t.Errorf("Contains(005) should be true")
+	}
+	if s.Contains([]byte("009")) {
+		t.Errorf("Contains(009) should be false")
+	}
+}
+
+func TestConcurrentSkiplistDuplicate(t *testing.T) {
+	s := buildConcurrentSkiplist()
+
+	if err := s.Add([]byte("003"), []byte("003-again")); err != ErrDuplicateKey {
+		t.Errorf("Add(003) again should return ErrDuplicateKey, got %v", err)
+	}
+}
+
+func TestConcurrentSkiplistArenaFull(t *testing.T) {
+	// The head node alone costs concurrentNodeHeaderSize + concurrentMaxHeight*4
+	// bytes, so the arena needs headroom beyond that just to construct; 256
+	// bytes leaves enough room to construct but still exhausts after a
+	// handful of Adds.
+	s := NewConcurrentSkiplist(256)
+
+	var err error
+	for i := 0; i < 1000 && err == nil; i++ {
+		err = s.Add([]byte{byte(i)}, []byte{byte(i)})
+	}
+	if err != ErrArenaFull {
+		t.Errorf("Add should eventually return ErrArenaFull, got %v", err)
+	}
+}
+
+// TestConcurrentSkiplistAddConcurrently exercises the CAS retry loop and the
+// s.height growth race that buildConcurrentSkiplist's single-goroutine tests
+// above can't reach: run with -race to catch a data race in either.
+func TestConcurrentSkiplistAddConcurrently(t *testing.T) {
+	const goroutines = 20
+	const perGoroutine = 50
+
+	s := NewConcurrentSkiplist(1 << 20)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				key := []byte(fmt.Sprintf("%02d-%03d", g, i))
+				if err := s.Add(key, key); err != nil {
+					t.Errorf("Add(%s) returned error: %v", key, err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for g := 0; g < goroutines; g++ {
+		for i := 0; i < perGoroutine; i++ {
+			key := []byte(fmt.Sprintf("%02d-%03d", g, i))
+			v, err := s.Get(key)
+			if err != nil || string(v) != string(key) {
+				t.Errorf("Get(%s) = %s, %v; want %s, nil", key, v, err, key)
+			}
+		}
+	}
+
+	it := s.NewIterator()
+	var prev []byte
+	count := 0
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		if prev != nil && bytes.Compare(prev, it.Key()) >= 0 {
+			t.Fatalf("iterator out of order: %s then %s", prev, it.Key())
+		}
+		prev = append([]byte(nil), it.Key()...)
+		count++
+	}
+	if want := goroutines * perGoroutine; count != want {
+		t.Errorf("iterator visited %d keys, want %d", count, want)
+	}
+}
+
+func TestConcurrentSkiplistIterator(t *testing.T) {
+	s := buildConcurrentSkiplist()
+
+	it := s.NewIterator()
+	var keys []string
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+
+	want := []string{"000", "001", "003", "005", "008"}
+	if len(keys) != len(want) {
+		t.Fatalf("iterator walked %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("iterator walked %v, want %v", keys, want)
+			break
+		}
+	}
+}
@@ -17,7 +17,7 @@ const (
 	P        = 0.3
 )
 
-type skiplistNode[K constraints.Ordered, V any] struct {
+type skiplistNode[K any, V any] struct {
 	key      K
 	value    V
 	level    int
@@ -25,23 +25,32 @@ type skiplistNode[K constraints.Ordered, V any] struct {
 	backward *skiplistNode[K, V]
 }
 
-type Skiplist[K constraints.Ordered, V any] struct {
-	header *skiplistNode[K, V]
-	tail   *skiplistNode[K, V]
-	level  int
-	length int
+// Skiplist[K,V] orders keys by cmp, not by the built-in operators, so it
+// works for any K, not just constraints.Ordered ones. New supplies the
+// natural-ordering cmp; NewWithComparator lets the caller supply their own.
+type Skiplist[K any, V any] struct {
+	header      *skiplistNode[K, V]
+	tail        *skiplistNode[K, V]
+	level       int
+	length      int
+	cmp         func(a, b K) int
+	maxLevel    int
+	p           float64
+	rnd         *rand.Rand
+	encodeValue func(V) ([]byte, error)
+	decodeValue func([]byte) (V, error)
 }
 
-// New a empty skiplist, the zeroK & zeroV is used for nil/default value.
-// Not familiar with generic, zeroK & zeroV should be modified later.
-func New[K constraints.Ordered, V any]() *Skiplist[K, V] {
+// newSkiplist builds an empty Skiplist ordered by cmp, with a level
+// distribution geometrically tuned by maxLevel/p.
+func newSkiplist[K any, V any](cmp func(a, b K) int, maxLevel int, p float64) *Skiplist[K, V] {
 	var zeroK K
 	var zeroV V
 	header := &skiplistNode[K, V]{
 		zeroK,
 		zeroV,
-		MaxLevel,
-		make([]*skiplistNode[K, V], MaxLevel),
+		maxLevel,
+		make([]*skiplistNode[K, V], maxLevel),
 		nil,
 	}
 	return &Skiplist[K, V]{
@@ -49,9 +58,65 @@ func New[K constraints.Ordered, V any]() *Skiplist[K, V] {
 		nil,
 		1,
 		0,
+		cmp,
+		maxLevel,
+		p,
+		rand.New(rand.NewSource(time.Now().UnixNano())),
+		nil,
+		nil,
 	}
 }
 
+// naturalCompare orders K by its built-in </== operators.
+func naturalCompare[K constraints.Ordered](a, b K) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// New returns an empty skiplist whose keys are ordered by their built-in
+// </== operators, with the default MaxLevel/P level distribution.
+func New[K constraints.Ordered, V any]() *Skiplist[K, V] {
+	return newSkiplist[K, V](naturalCompare[K], MaxLevel, P)
+}
+
+// NewWithComparator returns an empty Skiplist ordered by less, which must
+// return a negative number if a < b, a positive number if a > b, and 0 if
+// a == b. This lifts the constraints.Ordered restriction so keys can be
+// []byte, structs, or an Ordered type with e.g. reversed ordering.
+func NewWithComparator[K any, V any](less func(a, b K) int) *Skiplist[K, V] {
+	return newSkiplist[K, V](less, MaxLevel, P)
+}
+
+// NewWithParams is like New, but lets the caller tune the geometric level
+// distribution: maxLevel bounds tower height, and p is the probability of
+// growing a tower by one more level (the classic Pugh paper uses p=0.5;
+// nutsdb uses maxLevel=32/p=0.25; New uses MaxLevel=12/P=0.3).
+func NewWithParams[K constraints.Ordered, V any](maxLevel int, p float64) *Skiplist[K, V] {
+	return newSkiplist[K, V](naturalCompare[K], maxLevel, p)
+}
+
+// Seed reseeds the Skiplist's level-generating RNG, for reproducible tests.
+func (sl *Skiplist[K, V]) Seed(seed int64) {
+	sl.rnd = rand.New(rand.NewSource(seed))
+}
+
+// NewWithCodec is like New, but also equips the Skiplist with the
+// encode/decode pair Snapshot and Load use to turn values into bytes. Pass
+// the same codec to Load when reloading a snapshot written by a Skiplist
+// built this way.
+func NewWithCodec[K constraints.Ordered, V any](encode func(V) ([]byte, error), decode func([]byte) (V, error)) *Skiplist[K, V] {
+	sl := New[K, V]()
+	sl.encodeValue = encode
+	sl.decodeValue = decode
+	return sl
+}
+
 // Put a new key/value into skiplist. If exists, update the value.
 func (sl *Skiplist[K, V]) Put(k K, v V) error {
 	// if this is the first element, just insert into level 0
@@ -79,7 +144,7 @@ func (sl *Skiplist[K, V]) Put(k K, v V) error {
 	}
 
 	// if not found, insert a new node
-	level := randomLevel()
+	level := sl.randomLevel()
 	n := &skiplistNode[K, V]{
 		key:      k,
 		value:    v,
@@ -139,27 +204,104 @@ func (sl *Skiplist[K, V]) Length() int {
 	return sl.length
 }
 
-// RangeByKey return range query with start key and end key.
-func (sl *Skiplist[K, V]) RangeByKey(start K, end K) (map[K]V, error) {
-	if start > end {
-		return nil, errors.New("START key is great than END key")
+// Pair is a key/value entry, returned by the range helpers in sorted order.
+type Pair[K any, V any] struct {
+	Key   K
+	Value V
+}
+
+// Iterator walks the level-0 forward/backward pointers of a Skiplist directly,
+// so results come back in sorted order. It follows the pattern used by the
+// memtable skiplists in RocksDB/Pebble/goleveldb. An Iterator is not safe for
+// use by multiple goroutines, nor across concurrent mutation of the Skiplist.
+type Iterator[K any, V any] struct {
+	sl   *Skiplist[K, V]
+	node *skiplistNode[K, V]
+}
+
+// NewIterator returns an Iterator positioned at the first (smallest) key.
+func (sl *Skiplist[K, V]) NewIterator() *Iterator[K, V] {
+	return &Iterator[K, V]{sl: sl, node: sl.header.forward[0]}
+}
+
+// NewReverseIterator returns an Iterator positioned at the last (largest) key.
+func (sl *Skiplist[K, V]) NewReverseIterator() *Iterator[K, V] {
+	return &Iterator[K, V]{sl: sl, node: sl.tail}
+}
+
+// Valid reports whether the iterator is positioned at a valid entry.
+func (it *Iterator[K, V]) Valid() bool {
+	return it.node != nil
+}
+
+// Key returns the key at the current position. Valid() must be true.
+func (it *Iterator[K, V]) Key() K {
+	return it.node.key
+}
+
+// Value returns the value at the current position. Valid() must be true.
+func (it *Iterator[K, V]) Value() V {
+	return it.node.value
+}
+
+// Next advances the iterator to the next key in ascending order.
+func (it *Iterator[K, V]) Next() {
+	it.node = it.node.forward[0]
+}
+
+// Prev moves the iterator to the previous key in ascending order.
+func (it *Iterator[K, V]) Prev() {
+	if it.node.backward == it.sl.header {
+		it.node = nil
+		return
 	}
+	it.node = it.node.backward
+}
 
-	result := make(map[K]V)
-	found, node, updates := sl.find(start)
-	if !found {
-		node = updates[0].forward[0]
+// Seek positions the iterator at the first key >= key.
+func (it *Iterator[K, V]) Seek(key K) {
+	found, node, updates := it.sl.find(key)
+	if found {
+		it.node = node
+		return
+	}
+	it.node = updates[0].forward[0]
+}
+
+// SeekToFirst positions the iterator at the first (smallest) key.
+func (it *Iterator[K, V]) SeekToFirst() {
+	it.node = it.sl.header.forward[0]
+}
+
+// SeekToLast positions the iterator at the last (largest) key.
+func (it *Iterator[K, V]) SeekToLast() {
+	it.node = it.sl.tail
+}
+
+// Close releases the iterator. It is safe but unnecessary to call more than once.
+func (it *Iterator[K, V]) Close() error {
+	it.node = nil
+	it.sl = nil
+	return nil
+}
+
+// RangeByKey return range query with start key and end key, in ascending order.
+func (sl *Skiplist[K, V]) RangeByKey(start K, end K) ([]Pair[K, V], error) {
+	if sl.cmp(start, end) > 0 {
+		return nil, errors.New("START key is great than END key")
 	}
 
-	for ; node != nil && node.key <= end; node = node.forward[0] {
-		result[node.key] = node.value
+	result := make([]Pair[K, V], 0)
+	it := sl.NewIterator()
+	for it.Seek(start); it.Valid() && sl.cmp(it.Key(), end) <= 0; it.Next() {
+		result = append(result, Pair[K, V]{it.Key(), it.Value()})
 	}
 
 	return result, nil
 }
 
-// RangeByCount return range query with start and count.
-func (sl *Skiplist[K, V]) RangeByCount(start K, count int) (map[K]V, error) {
+// RangeByCount return range query with start and count, in the direction of the query.
+func (sl *Skiplist[K, V]) RangeByCount(start K, count int) ([]Pair[K, V], error) {
 	if count == 0 {
 		return nil, errors.New("Zero COUNT")
 	}
@@ -171,7 +313,7 @@ func (sl *Skiplist[K, V]) RangeByCount(start K, count int) (map[K]V, error) {
 		forward = false
 	}
 
-	result := make(map[K]V)
+	result := make([]Pair[K, V], 0, count)
 	found, node, updates := sl.find(start)
 
 	// If not found, set the node to updates[0].forward[0] when count>=0, or to updates[0] when count<0
@@ -185,7 +327,7 @@ func (sl *Skiplist[K, V]) RangeByCount(start K, count int) (map[K]V, error) {
 
 	// Get the query result
 	for c := 0; node != nil && node != sl.header && c < count; c++ {
-		result[node.key] = node.value
+		result = append(result, Pair[K, V]{node.key, node.value})
 		if forward {
 			node = node.forward[0]
 		} else {
@@ -196,8 +338,8 @@ func (sl *Skiplist[K, V]) RangeByCount(start K, count int) (map[K]V, error) {
 	return result, nil
 }
 
-// RangeByIndex return range query with start and count.
-func (sl *Skiplist[K, V]) RangeByIndex(start int, count int) (map[K]V, error) {
+// RangeByIndex return range query with start and count, in the direction of the query.
+func (sl *Skiplist[K, V]) RangeByIndex(start int, count int) ([]Pair[K, V], error) {
 	if count == 0 {
 		return nil, errors.New("Zero COUNT")
 	}
@@ -212,7 +354,7 @@ func (sl *Skiplist[K, V]) RangeByIndex(start int, count int) (map[K]V, error) {
 		forward = false
 	}
 
-	result := make(map[K]V)
+	result := make([]Pair[K, V], 0, count)
 
 	// Find the START node
 	node := sl.header.forward[0]
@@ -229,7 +371,7 @@ func (sl *Skiplist[K, V]) RangeByIndex(start int, count int) (map[K]V, error) {
 
 	// Get the query result
 	for c := 0; node != nil && node != sl.header && c < count; c++ {
-		result[node.key] = node.value
+		result = append(result, Pair[K, V]{node.key, node.value})
 		if forward {
 			node = node.forward[0]
 		} else {
@@ -242,19 +384,19 @@ func (sl *Skiplist[K, V]) RangeByIndex(start int, count int) (map[K]V, error) {
 
 // find the key from Skiplist, and try to return update nodes to insert/delete.
 func (sl *Skiplist[K, V]) find(key K) (found bool, node *skiplistNode[K, V], updates []*skiplistNode[K, V]) {
-	updates = make([]*skiplistNode[K, V], MaxLevel)
+	updates = make([]*skiplistNode[K, V], sl.maxLevel)
 
 	c := sl.header
 	found = false
 	node = nil
 
-	for i := MaxLevel - 1; i >= 0; i-- {
-		for ; c.forward[i] != nil && c.forward[i].key < key; c = c.forward[i] {
+	for i := sl.maxLevel - 1; i >= 0; i-- {
+		for ; c.forward[i] != nil && sl.cmp(c.forward[i].key, key) < 0; c = c.forward[i] {
 			// Forward to the node
 		}
 
 		// Found
-		if c.forward[i] != nil && c.forward[i].key == key {
+		if c.forward[i] != nil && sl.cmp(c.forward[i].key, key) == 0 {
 			found = true
 			node = c.forward[i]
 		}
@@ -263,19 +405,21 @@ func (sl *Skiplist[K, V]) find(key K) (found bool, node *skiplistNode[K, V], upd
 	return
 }
 
-// randomLevel generates skiplist node level.
-func randomLevel() int {
+// randomLevel generates a skiplist node level using sl's own RNG, seeded
+// once at construction instead of reseeding on every call: reseeding from
+// time.Now().UnixNano() on every insert is slow, and inserts that land in
+// the same nanosecond get correlated (identical) levels.
+func (sl *Skiplist[K, V]) randomLevel() int {
 	level := 1
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	for {
-		v := r.Uint32()
-		if float32(v&0xFFFF) > float32(P*0xFFFF) {
+		v := sl.rnd.Uint32()
+		if float32(v&0xFFFF) > float32(sl.p*0xFFFF) {
 			break
 		}
 		level++
 	}
-	if level > MaxLevel {
-		return MaxLevel
+	if level > sl.maxLevel {
+		return sl.maxLevel
 	}
 	return level
 }
@@ -1,11 +1,12 @@
 package skiplist
 
 import (
+	"bytes"
 	"testing"
 )
 
-func buildSkiplist() *Skiplist {
-	sl := New()
+func buildSkiplist() *Skiplist[string, string] {
+	sl := New[string, string]()
 
 	sl.Put("000", "000")
 	sl.Put("001", "001")
@@ -68,71 +69,206 @@ func TestDel(t *testing.T) {
 	}
 }
 
+// pairsHaveKeys reports whether r contains exactly the keys of want, each with
+// the value want maps it to. Order is not checked.
+func pairsHaveKeys(r []Pair[string, string], want map[string]string) bool {
+	if len(r) != len(want) {
+		return false
+	}
+	for _, p := range r {
+		v, ok := want[p.Key]
+		if !ok || v != p.Value {
+			return false
+		}
+	}
+	return true
+}
+
 func TestRange(t *testing.T) {
 	sl := buildSkiplist()
 
 	r, _ := sl.RangeByKey("001", "003")
-	if len(r) != 2 || r["001"] != "001" || r["003"] != "003" {
+	if !pairsHaveKeys(r, map[string]string{"001": "001", "003": "003"}) {
 		t.Errorf("RangeByKey error, the result=%v", r)
 	}
 
 	r, _ = sl.RangeByCount("002", 2)
-	if len(r) != 2 || r["003"] != "003" || r["004"] != "004-rewrite" {
+	if !pairsHaveKeys(r, map[string]string{"003": "003", "004": "004-rewrite"}) {
 		t.Errorf("RangeByCount(002, 2) error, the result=%v", r)
 	}
 
 	r, _ = sl.RangeByCount("008", 2)
-	if len(r) != 1 || r["008"] != "008" {
+	if !pairsHaveKeys(r, map[string]string{"008": "008"}) {
 		t.Errorf("RangeByCount(008, 2) error, the result=%v", r)
 	}
 
 	r, _ = sl.RangeByCount("002", -2)
-	if len(r) != 2 || r["000"] != "000" || r["001"] != "001" {
+	if !pairsHaveKeys(r, map[string]string{"000": "000", "001": "001"}) {
 		t.Errorf("RangeByCount(002, -2), the result=%v", r)
 	}
 
 	r, _ = sl.RangeByCount("008", -2)
-	if len(r) != 2 || r["008"] != "008" || r["007"] != "007" {
+	if !pairsHaveKeys(r, map[string]string{"008": "008", "007": "007"}) {
 		t.Errorf("RangeByCount(008, -2) error, the result=%v", r)
 	}
 
 	r, _ = sl.RangeByCount("008", -2)
-	if len(r) != 2 || r["008"] != "008" || r["007"] != "007" {
+	if !pairsHaveKeys(r, map[string]string{"008": "008", "007": "007"}) {
 		t.Errorf("RangeByCount(009, -2) error, the result=%v", r)
 	}
 
 	r, _ = sl.RangeByIndex(3, 2)
-	if len(r) != 2 || r["004"] != "004-rewrite" || r["005"] != "005" {
+	if !pairsHaveKeys(r, map[string]string{"004": "004-rewrite", "005": "005"}) {
 		t.Errorf("RangeByIndex(3, 2), the result=%v", r)
 	}
 
 	r, _ = sl.RangeByIndex(-1, 2)
-	if len(r) != 1 || r["008"] != "008" {
+	if !pairsHaveKeys(r, map[string]string{"008": "008"}) {
 		t.Errorf("RangeByIndex(-1, 2), the result=%v", r)
 	}
 
 	r, _ = sl.RangeByIndex(-2, 2)
-	if len(r) != 2 || r["008"] != "008" || r["007"] != "007" {
+	if !pairsHaveKeys(r, map[string]string{"008": "008", "007": "007"}) {
 		t.Errorf("RangeByIndex(-2, 2), the result=%v", r)
 	}
 
 	r, _ = sl.RangeByIndex(-3, 2)
-	if len(r) != 2 || r["006"] != "006" || r["007"] != "007" {
+	if !pairsHaveKeys(r, map[string]string{"006": "006", "007": "007"}) {
 		t.Errorf("RangeByIndex(-3, 2), the result=%v", r)
 	}
 
 	r, _ = sl.RangeByIndex(-1, -2)
-	if len(r) != 2 || r["008"] != "008" || r["007"] != "007" {
+	if !pairsHaveKeys(r, map[string]string{"008": "008", "007": "007"}) {
 		t.Errorf("RangeByIndex(-1, -2), the result=%v", r)
 	}
 
 	r, _ = sl.RangeByIndex(-2, -2)
-	if len(r) != 2 || r["006"] != "006" || r["007"] != "007" {
+	if !pairsHaveKeys(r, map[string]string{"006": "006", "007": "007"}) {
 		t.Errorf("RangeByIndex(-2, -2), the result=%v", r)
 	}
 
 	r, _ = sl.RangeByIndex(-5, -5)
-	if len(r) != 4 || r["004"] != "004-rewrite" || r["003"] != "003" || r["001"] != "001" || r["000"] != "000" {
+	if !pairsHaveKeys(r, map[string]string{"004": "004-rewrite", "003": "003", "001": "001", "000": "000"}) {
 		t.Errorf("RangeByIndex(-5, 5), the result=%v", r)
 	}
 }
+
+func TestNewWithComparator(t *testing.T) {
+	sl := NewWithComparator[[]byte, string](bytes.Compare)
+
+	sl.Put([]byte("b"), "b")
+	sl.Put([]byte("a"), "a")
+	sl.Put([]byte("c"), "c")
+
+	v, err := sl.Get([]byte("a"))
+	if err != nil || v != "a" {
+		t.Errorf("Get(a) = %s, %v; want a, nil", v, err)
+	}
+
+	it := sl.NewIterator()
+	var keys []string
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	want := []string{"a", "b", "c"}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("NewWithComparator walked %v, want %v", keys, want)
+			break
+		}
+	}
+}
+
+func TestNewWithComparatorReversed(t *testing.T) {
+	sl := NewWithComparator[int, string](func(a, b int) int { return b - a })
+
+	sl.Put(1, "one")
+	sl.Put(3, "three")
+	sl.Put(2, "two")
+
+	it := sl.NewIterator()
+	var keys []int
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	want := []int{3, 2, 1}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("reversed comparator walked %v, want %v", keys, want)
+			break
+		}
+	}
+}
+
+func TestNewWithParams(t *testing.T) {
+	sl := NewWithParams[int, int](32, 0.25)
+
+	for i := 0; i < 500; i++ {
+		sl.Put(i, i*i)
+	}
+
+	if l := sl.Length(); l != 500 {
+		t.Errorf("Length() is %d, should be 500", l)
+	}
+
+	v, err := sl.Get(250)
+	if err != nil || v != 250*250 {
+		t.Errorf("Get(250) = %d, %v; want %d, nil", v, err, 250*250)
+	}
+}
+
+func TestSeedIsReproducible(t *testing.T) {
+	sl1 := New[int, int]()
+	sl1.Seed(42)
+	sl2 := New[int, int]()
+	sl2.Seed(42)
+
+	for i := 0; i < 50; i++ {
+		if l1, l2 := sl1.randomLevel(), sl2.randomLevel(); l1 != l2 {
+			t.Fatalf("randomLevel() diverged at call %d: %d != %d", i, l1, l2)
+		}
+	}
+}
+
+func TestIterator(t *testing.T) {
+	sl := buildSkiplist()
+
+	it := sl.NewIterator()
+	defer it.Close()
+
+	var keys []string
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	want := []string{"000", "001", "003", "004", "005", "006", "007", "008"}
+	if len(keys) != len(want) {
+		t.Fatalf("NewIterator() walked %v, want %v", keys, want)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("NewIterator() walked %v, want %v", keys, want)
+			break
+		}
+	}
+
+	rit := sl.NewReverseIterator()
+	defer rit.Close()
+
+	keys = nil
+	for rit.SeekToLast(); rit.Valid(); rit.Prev() {
+		keys = append(keys, rit.Key())
+	}
+	for i, j := 0, len(want)-1; i < len(want); i, j = i+1, j-1 {
+		if keys[i] != want[j] {
+			t.Errorf("NewReverseIterator() walked %v, want reverse of %v", keys, want)
+			break
+		}
+	}
+
+	seekIt := sl.NewIterator()
+	defer seekIt.Close()
+	seekIt.Seek("004")
+	if !seekIt.Valid() || seekIt.Key() != "004" || seekIt.Value() != "004-rewrite" {
+		t.Errorf("Seek(004) landed on key=%v value=%v", seekIt.Key(), seekIt.Value())
+	}
+}
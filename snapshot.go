@@ -0,0 +1,229 @@
+// Copyright (c) 2021, Rod Dong <rod.dong@me.com> All rights reserved.
+//
+// Use of this source code is governed by The MIT License.
+
+package skiplist
+
+import (
+	"bufio"
+	"bytes"
+	"constraints"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io"
+	"os"
+)
+
+// writeKey gob-encodes key and writes it to w as a length-prefixed section.
+// It is not constrained to constraints.Ordered: Snapshot calls it from a
+// Skiplist[K,V] method where K is only `any` (Skiplist keys are ordered by
+// sl.cmp, not by gob-encodability), and gob's reflection-based encoding
+// needs no ordering to serialize a value.
+func writeKey[K any](w io.Writer, key K) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(key); err != nil {
+		return err
+	}
+	return writeSection(w, buf.Bytes())
+}
+
+func readKey[K any](r io.Reader) (K, error) {
+	var key K
+	buf, err := readSection(r)
+	if err != nil {
+		return key, err
+	}
+	err = gob.NewDecoder(bytes.NewReader(buf)).Decode(&key)
+	return key, err
+}
+
+func writeSection(w io.Writer, b []byte) error {
+	if err := binary.Write(w, binary.LittleEndian, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func readSection(r io.Reader) ([]byte, error) {
+	var n uint32
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	_, err := io.ReadFull(r, buf)
+	return buf, err
+}
+
+// Snapshot writes the skiplist's key/value pairs, in sorted order, to w in a
+// compact length-prefixed binary format. Values are serialized with the
+// encode function the Skiplist was built with, so sl must have been
+// constructed via NewWithCodec.
+func (sl *Skiplist[K, V]) Snapshot(w io.Writer) error {
+	if sl.encodeValue == nil {
+		return errors.New("skiplist: Snapshot requires a Skiplist built with NewWithCodec")
+	}
+
+	bw := bufio.NewWriter(w)
+	it := sl.NewIterator()
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		if err := writeKey(bw, it.Key()); err != nil {
+			return err
+		}
+		valueBytes, err := sl.encodeValue(it.Value())
+		if err != nil {
+			return err
+		}
+		if err := writeSection(bw, valueBytes); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Load rebuilds a Skiplist from a snapshot written by Snapshot. encode/decode
+// become the resulting Skiplist's codec, as if it had been built with
+// NewWithCodec.
+func Load[K constraints.Ordered, V any](r io.Reader, encode func(V) ([]byte, error), decode func([]byte) (V, error)) (*Skiplist[K, V], error) {
+	sl := NewWithCodec[K, V](encode, decode)
+
+	for {
+		key, err := readKey[K](r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		valueBytes, err := readSection(r)
+		if err != nil {
+			return nil, err
+		}
+		value, err := decode(valueBytes)
+		if err != nil {
+			return nil, err
+		}
+		if err := sl.Put(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return sl, nil
+}
+
+// logOp tags each record in a Log's append-only file.
+type logOp byte
+
+const (
+	logOpPut logOp = 0
+	logOpDel logOp = 1
+)
+
+// Log wraps a Skiplist with an append-only on-disk log: every Put/Del is
+// recorded to the log file as it happens, so OpenLog can replay the file to
+// rebuild the same Skiplist the next time the process starts.
+type Log[K constraints.Ordered, V any] struct {
+	sl     *Skiplist[K, V]
+	file   *os.File
+	encode func(V) ([]byte, error)
+}
+
+// OpenLog opens (creating if necessary) the log file at path, replays any
+// existing entries into a fresh Skiplist, and returns a Log that appends
+// further Put/Del calls to the same file.
+func OpenLog[K constraints.Ordered, V any](path string, encode func(V) ([]byte, error), decode func([]byte) (V, error)) (*Log[K, V], error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	sl := NewWithCodec[K, V](encode, decode)
+	if err := replayLog[K, V](f, sl, decode); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &Log[K, V]{sl: sl, file: f, encode: encode}, nil
+}
+
+func replayLog[K constraints.Ordered, V any](r io.Reader, sl *Skiplist[K, V], decode func([]byte) (V, error)) error {
+	for {
+		var op logOp
+		if err := binary.Read(r, binary.LittleEndian, &op); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		key, err := readKey[K](r)
+		if err != nil {
+			return err
+		}
+
+		switch op {
+		case logOpPut:
+			valueBytes, err := readSection(r)
+			if err != nil {
+				return err
+			}
+			value, err := decode(valueBytes)
+			if err != nil {
+				return err
+			}
+			if err := sl.Put(key, value); err != nil {
+				return err
+			}
+		case logOpDel:
+			// a key logged before the Skiplist ever saw it is a no-op delete
+			_ = sl.Del(key)
+		default:
+			return errors.New("skiplist: corrupt log entry")
+		}
+	}
+}
+
+// Skiplist returns the Skiplist kept in sync with the log.
+func (l *Log[K, V]) Skiplist() *Skiplist[K, V] {
+	return l.sl
+}
+
+// Put records a Put in the log, then applies it to the underlying Skiplist.
+func (l *Log[K, V]) Put(k K, v V) error {
+	valueBytes, err := l.encode(v)
+	if err != nil {
+		return err
+	}
+	if err := binary.Write(l.file, binary.LittleEndian, logOpPut); err != nil {
+		return err
+	}
+	if err := writeKey(l.file, k); err != nil {
+		return err
+	}
+	if err := writeSection(l.file, valueBytes); err != nil {
+		return err
+	}
+	return l.sl.Put(k, v)
+}
+
+// Del records a Del in the log, then applies it to the underlying Skiplist.
+func (l *Log[K, V]) Del(k K) error {
+	if err := binary.Write(l.file, binary.LittleEndian, logOpDel); err != nil {
+		return err
+	}
+	if err := writeKey(l.file, k); err != nil {
+		return err
+	}
+	return l.sl.Del(k)
+}
+
+// Close closes the underlying log file.
+func (l *Log[K, V]) Close() error {
+	return l.file.Close()
+}
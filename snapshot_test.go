@@ -0,0 +1,110 @@
+package skiplist
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func encodeString(v string) ([]byte, error) { return []byte(v), nil }
+func decodeString(b []byte) (string, error) { return string(b), nil }
+
+func buildCodecSkiplist() *Skiplist[string, string] {
+	sl := NewWithCodec[string, string](encodeString, decodeString)
+
+	sl.Put("003", "003")
+	sl.Put("001", "001")
+	sl.Put("002", "002")
+
+	return sl
+}
+
+func TestSnapshotRequiresCodec(t *testing.T) {
+	sl := New[string, string]()
+	sl.Put("001", "001")
+
+	var buf bytes.Buffer
+	if err := sl.Snapshot(&buf); err == nil {
+		t.Errorf("Snapshot() on a Skiplist without a codec should return an error")
+	}
+}
+
+func TestSnapshotAndLoad(t *testing.T) {
+	sl := buildCodecSkiplist()
+
+	var buf bytes.Buffer
+	if err := sl.Snapshot(&buf); err != nil {
+		t.Fatalf("Snapshot() returned error: %v", err)
+	}
+
+	loaded, err := Load[string, string](&buf, encodeString, decodeString)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	if l := loaded.Length(); l != 3 {
+		t.Errorf("Load() Length() = %d, want 3", l)
+	}
+
+	v, err := loaded.Get("002")
+	if err != nil || v != "002" {
+		t.Errorf("Load() Get(002) = %s, %v; want 002, nil", v, err)
+	}
+
+	it := loaded.NewIterator()
+	var keys []string
+	for it.SeekToFirst(); it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	want := []string{"001", "002", "003"}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("Load() iterated %v, want %v", keys, want)
+			break
+		}
+	}
+}
+
+func TestOpenLogReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "skiplist.log")
+
+	l, err := OpenLog[string, string](path, encodeString, decodeString)
+	if err != nil {
+		t.Fatalf("OpenLog() returned error: %v", err)
+	}
+
+	if err := l.Put("001", "001"); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	if err := l.Put("002", "002"); err != nil {
+		t.Fatalf("Put() returned error: %v", err)
+	}
+	if err := l.Del("001"); err != nil {
+		t.Fatalf("Del() returned error: %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() returned error: %v", err)
+	}
+
+	reopened, err := OpenLog[string, string](path, encodeString, decodeString)
+	if err != nil {
+		t.Fatalf("OpenLog() replay returned error: %v", err)
+	}
+	defer reopened.Close()
+
+	sl := reopened.Skiplist()
+	if l := sl.Length(); l != 1 {
+		t.Errorf("replayed Skiplist Length() = %d, want 1", l)
+	}
+	if _, err := sl.Get("001"); err == nil {
+		t.Errorf("replayed Skiplist should not contain 001 (deleted)")
+	}
+	if v, err := sl.Get("002"); err != nil || v != "002" {
+		t.Errorf("replayed Skiplist Get(002) = %s, %v; want 002, nil", v, err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("log file should still exist: %v", err)
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright (c) 2021, Rod Dong <rod.dong@me.com> All rights reserved.
+//
+// Use of this source code is governed by The MIT License.
+
+package skiplist
+
+import (
+	"constraints"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// zsetNode is a node of a SortedSet, ordered by (score, member). Besides the
+// forward tower every level also records a span: the number of level-0 nodes
+// that forward pointer skips over. Spans are what let GetByRank/GetRank run
+// in O(log N), following the classic Redis zskiplist (zsl) algorithm.
+type zsetNode[M constraints.Ordered] struct {
+	member   M
+	score    float64
+	level    int
+	forward  []*zsetNode[M]
+	span     []int
+	backward *zsetNode[M]
+}
+
+// SortedSet implements Redis-style ZSET semantics: members are ordered by a
+// float64 score with the member itself as tie-breaker. memberScore is a side
+// index from member to its current score, the same way Redis keeps a
+// separate dict alongside its zskiplist: the skiplist is ordered by
+// (score, member), so a descent that only knows the member (not yet its
+// score) cannot reliably find it by walking the towers alone.
+type SortedSet[M constraints.Ordered] struct {
+	header      *zsetNode[M]
+	tail        *zsetNode[M]
+	level       int
+	length      int
+	rnd         *rand.Rand
+	memberScore map[M]float64
+}
+
+// NewSortedSet returns an empty SortedSet.
+func NewSortedSet[M constraints.Ordered]() *SortedSet[M] {
+	var zeroM M
+	header := &zsetNode[M]{
+		member:  zeroM,
+		level:   MaxLevel,
+		forward: make([]*zsetNode[M], MaxLevel),
+		span:    make([]int, MaxLevel),
+	}
+	return &SortedSet[M]{
+		header:      header,
+		level:       1,
+		rnd:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		memberScore: make(map[M]float64),
+	}
+}
+
+// less reports whether (scoreA, memberA) sorts before (scoreB, memberB).
+func less[M constraints.Ordered](scoreA float64, memberA M, scoreB float64, memberB M) bool {
+	if scoreA != scoreB {
+		return scoreA < scoreB
+	}
+	return memberA < memberB
+}
+
+// Length return the number of members in the SortedSet.
+func (zs *SortedSet[M]) Length() int {
+	return zs.length
+}
+
+// Put inserts member with score, or moves it to its new position if it
+// already exists.
+func (zs *SortedSet[M]) Put(member M, score float64) error {
+	if oldScore, ok := zs.memberScore[member]; ok {
+		if oldScore == score {
+			// same (score, member) already present, nothing to do
+			return nil
+		}
+		// member already exists under a different score, remove it first
+		if err := zs.del(member, oldScore); err != nil {
+			return err
+		}
+	}
+
+	updates := make([]*zsetNode[M], MaxLevel)
+	ranks := make([]int, MaxLevel)
+
+	c := zs.header
+	for i := zs.level - 1; i >= 0; i-- {
+		if i == zs.level-1 {
+			ranks[i] = 0
+		} else {
+			ranks[i] = ranks[i+1]
+		}
+		for c.forward[i] != nil && less(c.forward[i].score, c.forward[i].member, score, member) {
+			ranks[i] += c.span[i]
+			c = c.forward[i]
+		}
+		updates[i] = c
+	}
+
+	level := randomLevelWith(zs.rnd)
+	if level > zs.level {
+		for i := zs.level; i < level; i++ {
+			ranks[i] = 0
+			updates[i] = zs.header
+			updates[i].span[i] = zs.length
+		}
+		zs.level = level
+	}
+
+	n := &zsetNode[M]{
+		member:  member,
+		score:   score,
+		level:   level,
+		forward: make([]*zsetNode[M], level),
+		span:    make([]int, level),
+	}
+
+	for i := 0; i < level; i++ {
+		n.forward[i] = updates[i].forward[i]
+		updates[i].forward[i] = n
+
+		n.span[i] = updates[i].span[i] - (ranks[0] - ranks[i])
+		updates[i].span[i] = ranks[0] - ranks[i] + 1
+	}
+
+	// increment span for levels above the new node's height
+	for i := level; i < zs.level; i++ {
+		updates[i].span[i]++
+	}
+
+	if updates[0] != zs.header {
+		n.backward = updates[0]
+	} else {
+		n.backward = nil
+	}
+	if n.forward[0] != nil {
+		n.forward[0].backward = n
+	} else {
+		zs.tail = n
+	}
+
+	zs.memberScore[member] = score
+	zs.length++
+	return nil
+}
+
+// Get returns the score of member. If not found, error(Not Found).
+func (zs *SortedSet[M]) Get(member M) (float64, error) {
+	score, ok := zs.memberScore[member]
+	if !ok {
+		return 0, errors.New("Not Found")
+	}
+	return score, nil
+}
+
+// Del removes member from the SortedSet.
+func (zs *SortedSet[M]) Del(member M) error {
+	score, err := zs.Get(member)
+	if err != nil {
+		return err
+	}
+	return zs.del(member, score)
+}
+
+func (zs *SortedSet[M]) del(member M, score float64) error {
+	found, node, updates := zs.find(member, score)
+	if !found {
+		return errors.New("Not Found")
+	}
+
+	for i := 0; i < zs.level; i++ {
+		if updates[i].forward[i] == node {
+			updates[i].span[i] += node.span[i] - 1
+			updates[i].forward[i] = node.forward[i]
+		} else {
+			updates[i].span[i]--
+		}
+	}
+
+	if node.forward[0] != nil {
+		node.forward[0].backward = node.backward
+	} else {
+		zs.tail = node.backward
+	}
+
+	for zs.level > 1 && zs.header.forward[zs.level-1] == nil {
+		zs.level--
+	}
+
+	delete(zs.memberScore, member)
+	zs.length--
+	return nil
+}
+
+// GetRank returns the 0-based rank of member in ascending score order, or
+// error(Not Found) if member is absent.
+func (zs *SortedSet[M]) GetRank(member M) (int, error) {
+	score, err := zs.Get(member)
+	if err != nil {
+		return 0, err
+	}
+
+	rank := 0
+	c := zs.header
+	for i := zs.level - 1; i >= 0; i-- {
+		for c.forward[i] != nil &&
+			(less(c.forward[i].score, c.forward[i].member, score, member) ||
+				(c.forward[i].score == score && c.forward[i].member == member)) {
+			rank += c.span[i]
+			c = c.forward[i]
+		}
+		if c.member == member && c.score == score && c != zs.header {
+			return rank - 1, nil
+		}
+	}
+	return 0, errors.New("Not Found")
+}
+
+// GetByRank returns the member and score at the given 0-based rank, in
+// ascending score order.
+func (zs *SortedSet[M]) GetByRank(rank int) (member M, score float64, err error) {
+	if rank < 0 || rank >= zs.length {
+		return member, 0, errors.New("Out of range")
+	}
+
+	traversed := -1
+	c := zs.header
+	for i := zs.level - 1; i >= 0; i-- {
+		for c.forward[i] != nil && traversed+c.span[i] <= rank {
+			traversed += c.span[i]
+			c = c.forward[i]
+		}
+		if traversed == rank {
+			return c.member, c.score, nil
+		}
+	}
+	return member, 0, errors.New("Out of range")
+}
+
+// RangeByScore returns the members whose score falls within [min, max], in
+// ascending score order.
+func (zs *SortedSet[M]) RangeByScore(min, max float64) ([]Pair[M, float64], error) {
+	if min > max {
+		return nil, errors.New("MIN score is greater than MAX score")
+	}
+
+	result := make([]Pair[M, float64], 0)
+	c := zs.header
+	for i := zs.level - 1; i >= 0; i-- {
+		for c.forward[i] != nil && c.forward[i].score < min {
+			c = c.forward[i]
+		}
+	}
+	c = c.forward[0]
+
+	for ; c != nil && c.score <= max; c = c.forward[0] {
+		result = append(result, Pair[M, float64]{c.member, c.score})
+	}
+	return result, nil
+}
+
+// RevRangeByRank returns the members with rank within [start, stop]
+// (inclusive, 0-based, descending score order numbering: rank 0 is the
+// highest score), delivered in descending score order, matching Redis
+// ZREVRANGE. Negative start/stop count back from the end, as with
+// RangeByIndex.
+func (zs *SortedSet[M]) RevRangeByRank(start, stop int) ([]Pair[M, float64], error) {
+	if start < 0 {
+		start += zs.length
+	}
+	if stop < 0 {
+		stop += zs.length
+	}
+	if start < 0 || start >= zs.length {
+		return nil, errors.New("Out of range")
+	}
+	if stop < start {
+		return []Pair[M, float64]{}, nil
+	}
+	if stop >= zs.length {
+		stop = zs.length - 1
+	}
+
+	result := make([]Pair[M, float64], 0, stop-start+1)
+	for i := start; i <= stop; i++ {
+		member, score, err := zs.GetByRank(zs.length - 1 - i)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, Pair[M, float64]{member, score})
+	}
+	return result, nil
+}
+
+// find the (score, member) pair in the SortedSet, and return update nodes to
+// insert/delete.
+func (zs *SortedSet[M]) find(member M, score float64) (found bool, node *zsetNode[M], updates []*zsetNode[M]) {
+	updates = make([]*zsetNode[M], MaxLevel)
+
+	c := zs.header
+	for i := zs.level - 1; i >= 0; i-- {
+		for c.forward[i] != nil && less(c.forward[i].score, c.forward[i].member, score, member) {
+			c = c.forward[i]
+		}
+		updates[i] = c
+	}
+
+	if c.forward[0] != nil && c.forward[0].score == score && c.forward[0].member == member {
+		found = true
+		node = c.forward[0]
+	}
+	return
+}
+
+// randomLevelWith generates a skiplist node level using r, following the same
+// geometric distribution as randomLevel.
+func randomLevelWith(r *rand.Rand) int {
+	level := 1
+	for {
+		v := r.Uint32()
+		if float32(v&0xFFFF) > float32(P*0xFFFF) {
+			break
+		}
+		level++
+	}
+	if level > MaxLevel {
+		return MaxLevel
+	}
+	return level
+}
@@ -0,0 +1,127 @@
+package skiplist
+
+import (
+	"testing"
+)
+
+func buildSortedSet() *SortedSet[string] {
+	zs := NewSortedSet[string]()
+
+	zs.Put("alice", 50)
+	zs.Put("bob", 80)
+	zs.Put("carol", 80)
+	zs.Put("dave", 10)
+	zs.Put("erin", 30)
+
+	return zs
+}
+
+func TestSortedSetLength(t *testing.T) {
+	zs := buildSortedSet()
+	if l := zs.Length(); l != 5 {
+		t.Errorf("Length() is %d, should be 5", l)
+	}
+}
+
+func TestSortedSetGetByRankAndGetRank(t *testing.T) {
+	zs := buildSortedSet()
+
+	// ascending by score, tie-broken by member: dave(10), erin(30), alice(50), bob(80), carol(80)
+	wantOrder := []string{"dave", "erin", "alice", "bob", "carol"}
+	for rank, member := range wantOrder {
+		m, _, err := zs.GetByRank(rank)
+		if err != nil || m != member {
+			t.Errorf("GetByRank(%d) = %s, %v; want %s", rank, m, err, member)
+		}
+
+		r, err := zs.GetRank(member)
+		if err != nil || r != rank {
+			t.Errorf("GetRank(%s) = %d, %v; want %d", member, r, err, rank)
+		}
+	}
+
+	if _, _, err := zs.GetByRank(5); err == nil {
+		t.Errorf("GetByRank(5) should return error(Out of range)")
+	}
+
+	if _, err := zs.GetRank("frank"); err == nil {
+		t.Errorf("GetRank(frank) should return error(Not Found)")
+	}
+}
+
+func TestSortedSetPutUpdatesScore(t *testing.T) {
+	zs := buildSortedSet()
+
+	zs.Put("dave", 90)
+	if l := zs.Length(); l != 5 {
+		t.Errorf("Length() after re-Put is %d, should still be 5", l)
+	}
+
+	score, err := zs.Get("dave")
+	if err != nil || score != 90 {
+		t.Errorf("Get(dave) = %v, %v; want 90", score, err)
+	}
+
+	rank, err := zs.GetRank("dave")
+	if err != nil || rank != 4 {
+		t.Errorf("GetRank(dave) = %d, %v; want 4", rank, err)
+	}
+}
+
+func TestSortedSetDel(t *testing.T) {
+	zs := buildSortedSet()
+
+	if err := zs.Del("frank"); err == nil {
+		t.Errorf("Del(frank) should return error(Not Found)")
+	}
+
+	if err := zs.Del("bob"); err != nil {
+		t.Errorf("Del(bob) should return nil error")
+	}
+	if l := zs.Length(); l != 4 {
+		t.Errorf("Length() after Del(bob) is %d, should be 4", l)
+	}
+	if _, err := zs.Get("bob"); err == nil {
+		t.Errorf("Get(bob) should return error(Not Found) after Del")
+	}
+}
+
+func TestSortedSetRangeByScore(t *testing.T) {
+	zs := buildSortedSet()
+
+	r, err := zs.RangeByScore(30, 80)
+	if err != nil {
+		t.Fatalf("RangeByScore(30, 80) returned error: %v", err)
+	}
+	want := []string{"erin", "alice", "bob", "carol"}
+	if len(r) != len(want) {
+		t.Fatalf("RangeByScore(30, 80) = %v, want members %v", r, want)
+	}
+	for i, p := range r {
+		if p.Key != want[i] {
+			t.Errorf("RangeByScore(30, 80)[%d] = %s, want %s", i, p.Key, want[i])
+		}
+	}
+
+	if _, err := zs.RangeByScore(80, 30); err == nil {
+		t.Errorf("RangeByScore(80, 30) should return error")
+	}
+}
+
+func TestSortedSetRevRangeByRank(t *testing.T) {
+	zs := buildSortedSet()
+
+	r, err := zs.RevRangeByRank(0, 1)
+	if err != nil {
+		t.Fatalf("RevRangeByRank(0, 1) returned error: %v", err)
+	}
+	want := []string{"carol", "bob"}
+	if len(r) != len(want) {
+		t.Fatalf("RevRangeByRank(0, 1) = %v, want members %v", r, want)
+	}
+	for i, p := range r {
+		if p.Key != want[i] {
+			t.Errorf("RevRangeByRank(0, 1)[%d] = %s, want %s", i, p.Key, want[i])
+		}
+	}
+}